@@ -0,0 +1,145 @@
+package loadtest
+
+import "time"
+
+// Config is the main configuration for the loadtest harness. It describes
+// the swarm of tapd nodes under test, the backing bitcoin node, and the
+// parameters that control how hard we drive the swarm.
+type Config struct {
+	// Nodes is the full pool of tapd instances that make up the swarm.
+	// Scenarios pick pairs or groups out of this pool by Name rather than
+	// assuming a fixed Alice/Bob topology, so the swarm can grow from two
+	// nodes to dozens without any code changes.
+	Nodes []*NodeConfig `long:"node" description:"a tapd node that's part of the swarm, may be specified multiple times"`
+
+	// Bitcoin is the backend bitcoind/btcd node shared by the whole
+	// swarm.
+	Bitcoin *BitcoinConfig `group:"bitcoin" namespace:"bitcoin"`
+
+	// Workload configures the concurrent mint/send/universe-sync
+	// scheduler that's run against the swarm.
+	Workload *WorkloadConfig `group:"workload" namespace:"workload"`
+}
+
+// NodeConfig describes a single tapd node in the swarm.
+type NodeConfig struct {
+	// Name uniquely identifies this node within the swarm (e.g. "alice",
+	// "bob", "node-03"). Scenarios and the workload scheduler address
+	// nodes by this name.
+	Name string `long:"name" description:"the name used to refer to this node within the swarm"`
+
+	// Tapd is the RPC connection info for this node's tapd instance.
+	Tapd *TapConfig `group:"tapd" namespace:"tapd"`
+}
+
+// TapConfig houses the connection information for a single tapd node.
+type TapConfig struct {
+	Host string `long:"host" description:"the host of the tapd instance"`
+	Port int    `long:"port" description:"the port of the tapd instance"`
+
+	TLSPath string `long:"tlspath" description:"the path to the TLS cert of the tapd instance"`
+	MacPath string `long:"macpath" description:"the path to the macaroon to use for the tapd instance"`
+
+	// MacaroonTimeout, if non-zero, adds a time-before caveat to the
+	// macaroon that expires it after the given duration. This lets
+	// scenarios assert that tapd rejects requests made with an expired
+	// macaroon.
+	MacaroonTimeout time.Duration `long:"macaroontimeout" description:"if set, adds a time-before caveat that expires the macaroon after this long"`
+
+	// MacaroonIPLock, if set, adds an ipaddr caveat that restricts the
+	// macaroon to requests originating from this source address. This
+	// lets scenarios assert that tapd rejects requests from a
+	// disallowed source address.
+	MacaroonIPLock string `long:"macarooniplock" description:"if set, adds an ipaddr caveat locking the macaroon to this source address"`
+
+	// ClientCertPath and ClientKeyPath, if both set, are loaded as a
+	// client TLS certificate/key pair to perform mutual TLS against tapd.
+	ClientCertPath string `long:"clientcertpath" description:"the path to the client TLS cert, for mTLS"`
+	ClientKeyPath  string `long:"clientkeypath" description:"the path to the client TLS key, for mTLS"`
+
+	// CipherSuites, if non-empty, restricts the TLS handshake to this
+	// list of cipher suites (by their Go constant name, e.g.
+	// "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"), so the harness can
+	// validate tapd's server-side enforcement of modern ciphers. Go's TLS
+	// stack ignores CipherSuites for TLS 1.3, so setting this also caps
+	// the handshake at TLS 1.2; only TLS 1.2 suite names are meaningful
+	// here.
+	CipherSuites []string `long:"ciphersuites" description:"restrict the TLS handshake to this list of TLS 1.2 cipher suites"`
+
+	// RemoteSigner, if set, points at the external signer process
+	// associated with this tapd node. The loadtest harness dials it
+	// directly and measures the bare signrpc round trip to it as a
+	// standalone number. tapd's own key derivation and Taproot output
+	// signing are not routed through it: they still use the embedded LND
+	// wallet whether or not this is set.
+	RemoteSigner *RemoteSignerConfig `group:"remotesigner" namespace:"remotesigner"`
+}
+
+// RemoteSignerConfig houses the connection information for the remote
+// signer process backing a tapd node.
+type RemoteSignerConfig struct {
+	Host string `long:"host" description:"the host of the remote signer"`
+	Port int    `long:"port" description:"the port of the remote signer"`
+
+	TLSPath string `long:"tlspath" description:"the path to the TLS cert of the remote signer"`
+	MacPath string `long:"macpath" description:"the path to the macaroon to use for the remote signer"`
+}
+
+// BitcoinConfig houses the connection information for the bitcoin backend
+// shared across the swarm.
+type BitcoinConfig struct {
+	Host     string `long:"host" description:"the host of the bitcoin backend"`
+	Port     int    `long:"port" description:"the port of the bitcoin backend"`
+	User     string `long:"user" description:"the user name to use for the bitcoin backend"`
+	Password string `long:"password" description:"the password to use for the bitcoin backend"`
+	TLSPath  string `long:"tlspath" description:"the path to the TLS cert of the bitcoin backend"`
+
+	// Backend selects which chain backend implementation to use. Valid
+	// values are "rpc" (the default, a direct JSON-RPC connection to
+	// btcd/bitcoind) and "neutrino" (an SPV connection, for realistic
+	// user-facing/mobile-style load tests).
+	Backend string `long:"backend" description:"the chain backend to use, either \"rpc\" or \"neutrino\""`
+
+	// Neutrino holds the extra configuration needed when Backend is set
+	// to "neutrino". It is ignored otherwise.
+	Neutrino *NeutrinoConfig `group:"neutrino" namespace:"neutrino"`
+}
+
+// NeutrinoConfig holds the parameters needed to spin up an SPV chain
+// backend.
+type NeutrinoConfig struct {
+	// Peers is the set of full node peers the neutrino.ChainService
+	// connects to for block and filter data.
+	Peers []string `long:"peers" description:"full node peers to connect the neutrino client to"`
+
+	// MiningNode points at a full node that's used purely to generate
+	// blocks on demand. Neutrino is a light client and can't mine, so
+	// every SPV-mode load test still needs a regular RPC connection for
+	// the MineBlocks calls the tests rely on.
+	MiningNode *BitcoinConfig `group:"miningnode" namespace:"miningnode"`
+}
+
+// WorkloadConfig controls how aggressively the workload scheduler drives the
+// swarm.
+type WorkloadConfig struct {
+	// FanOut is the number of concurrent workers the scheduler runs per
+	// node and activity kind (mint, send, universe-sync).
+	FanOut int `long:"fanout" description:"number of concurrent workers per node and activity kind"`
+
+	// QPS is the maximum number of requests per second the scheduler will
+	// issue against any single node, per activity kind, no matter how
+	// many of the FanOut workers are assigned to it.
+	QPS float64 `long:"qps" description:"maximum requests per second, per node"`
+}
+
+// NodeByName returns the node with the given name, or nil if no such node
+// exists in the swarm.
+func (c *Config) NodeByName(name string) *NodeConfig {
+	for _, node := range c.Nodes {
+		if node.Name == name {
+			return node
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package loadtest
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/macaroons"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/macaroon.v2"
+)
+
+// cipherSuitesByName maps the Go constant name of a TLS cipher suite to its
+// ID, so TapConfig.CipherSuites can be specified the same way they're named
+// in the crypto/tls docs.
+var cipherSuitesByName = func() map[string]uint16 {
+	byName := make(map[string]uint16)
+	for _, suite := range tls.CipherSuites() {
+		byName[suite.Name] = suite.ID
+	}
+
+	return byName
+}()
+
+// buildTLSConfig assembles the tls.Config used to dial a tapd node,
+// optionally adding a client certificate for mutual TLS and restricting the
+// handshake to a specific set of cipher suites.
+func buildTLSConfig(t *testing.T, cfg *TapConfig) *tls.Config {
+	tlsConfig := &tls.Config{}
+
+	if cfg.TLSPath != "" {
+		tlsCert, err := os.ReadFile(cfg.TLSPath)
+		require.NoError(t, err)
+
+		cp := x509.NewCertPool()
+		ok := cp.AppendCertsFromPEM(tlsCert)
+		require.True(t, ok)
+
+		tlsConfig.RootCAs = cp
+	}
+
+	if cfg.ClientCertPath != "" && cfg.ClientKeyPath != "" {
+		clientCert, err := tls.LoadX509KeyPair(
+			cfg.ClientCertPath, cfg.ClientKeyPath,
+		)
+		require.NoError(t, err)
+
+		tlsConfig.Certificates = []tls.Certificate{clientCert}
+	}
+
+	for _, name := range cfg.CipherSuites {
+		id, ok := cipherSuitesByName[name]
+		require.True(t, ok, "unknown cipher suite %q", name)
+
+		tlsConfig.CipherSuites = append(tlsConfig.CipherSuites, id)
+	}
+
+	// tls.Config.CipherSuites is ignored by the Go TLS stack for TLS 1.3,
+	// which always negotiates one of its own fixed suites. To actually
+	// restrict and test enforcement of cfg.CipherSuites, cap the
+	// handshake at TLS 1.2 whenever a restriction was requested.
+	if len(cfg.CipherSuites) > 0 {
+		tlsConfig.MinVersion = tls.VersionTLS12
+		tlsConfig.MaxVersion = tls.VersionTLS12
+	}
+
+	return tlsConfig
+}
+
+// constrainMacaroon adds the caveats configured on cfg to mac: a time-before
+// caveat that expires it after cfg.MacaroonTimeout, and/or an ipaddr caveat
+// that locks it to cfg.MacaroonIPLock.
+func constrainMacaroon(t *testing.T, cfg *TapConfig,
+	mac *macaroon.Macaroon) *macaroon.Macaroon {
+
+	var constraints []macaroons.Constraint
+
+	if cfg.MacaroonTimeout != 0 {
+		constraints = append(constraints, macaroons.TimeoutConstraint(
+			int64(cfg.MacaroonTimeout / time.Second),
+		))
+	}
+
+	if cfg.MacaroonIPLock != "" {
+		constraints = append(constraints, macaroons.IPLockConstraint(
+			cfg.MacaroonIPLock,
+		))
+	}
+
+	if len(constraints) == 0 {
+		return mac
+	}
+
+	constrained, err := macaroons.AddConstraints(mac, constraints...)
+	require.NoError(t, err)
+
+	return constrained
+}
@@ -0,0 +1,234 @@
+package loadtest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/rpcclient"
+	"github.com/btcsuite/btcwallet/walletdb"
+	_ "github.com/btcsuite/btcwallet/walletdb/bdb"
+	"github.com/lightninglabs/neutrino"
+	"github.com/stretchr/testify/require"
+)
+
+// chainBackend abstracts over the two ways the loadtest harness can talk to
+// the chain: a direct JSON-RPC connection to a full node, or an SPV
+// connection backed by neutrino. Scenarios only ever see this interface, so
+// they don't need to branch on which backend was configured.
+type chainBackend interface {
+	// MiningClient returns the RPC connection used to actually generate
+	// blocks. Neutrino is a light client and can't mine itself, so even
+	// in SPV mode this is a connection to a regular full node.
+	MiningClient() *rpcclient.Client
+
+	// BestBlockHeight returns the chain tip height as currently observed
+	// by this backend. For the SPV backend this comes from the
+	// neutrino.ChainService's own header index, not from the mining
+	// node, so it actually exercises the backend's block sync surface.
+	BestBlockHeight() (int32, error)
+
+	// WaitForBlockHeight blocks until this backend observes the chain
+	// tip at or above height, or ctx is done. Callers use this after
+	// mining a block via MiningClient to make sure the backend (in
+	// particular, a neutrino client connected to different peers than
+	// the mining node) has actually caught up.
+	WaitForBlockHeight(ctx context.Context, height int32) error
+}
+
+// rpcChainBackend is the chainBackend implementation used for the default,
+// full-node "rpc" backend. The same connection is used both for queries and
+// for mining.
+type rpcChainBackend struct {
+	*rpcclient.Client
+}
+
+// MiningClient returns the RPC connection used to actually generate blocks.
+//
+// NOTE: This is part of the chainBackend interface.
+func (r *rpcChainBackend) MiningClient() *rpcclient.Client {
+	return r.Client
+}
+
+// BestBlockHeight returns the chain tip height as seen by the full node.
+//
+// NOTE: This is part of the chainBackend interface.
+func (r *rpcChainBackend) BestBlockHeight() (int32, error) {
+	height, err := r.Client.GetBlockCount()
+	if err != nil {
+		return 0, err
+	}
+
+	return int32(height), nil
+}
+
+// WaitForBlockHeight blocks until the full node's tip reaches height.
+//
+// NOTE: This is part of the chainBackend interface.
+func (r *rpcChainBackend) WaitForBlockHeight(ctx context.Context,
+	height int32) error {
+
+	return pollForBlockHeight(ctx, r, height)
+}
+
+// neutrinoChainBackend is the chainBackend implementation used for the
+// SPV "neutrino" backend. It's backed by a neutrino.ChainService for the
+// actual SPV syncing/querying, plus a regular RPC connection to a dedicated
+// mining node since neutrino itself can't generate blocks.
+type neutrinoChainBackend struct {
+	chainService *neutrino.ChainService
+	miner        *rpcclient.Client
+}
+
+// MiningClient returns the RPC connection used to actually generate blocks.
+//
+// NOTE: This is part of the chainBackend interface.
+func (n *neutrinoChainBackend) MiningClient() *rpcclient.Client {
+	return n.miner
+}
+
+// BestBlockHeight returns the chain tip height as seen by the SPV client's
+// own header index.
+//
+// NOTE: This is part of the chainBackend interface.
+func (n *neutrinoChainBackend) BestBlockHeight() (int32, error) {
+	bestBlock, err := n.chainService.BestBlock()
+	if err != nil {
+		return 0, err
+	}
+
+	return bestBlock.Height, nil
+}
+
+// WaitForBlockHeight blocks until the SPV client's header index reaches
+// height, proving that it actually synced the block mined on the separate
+// mining node rather than just sitting idle.
+//
+// NOTE: This is part of the chainBackend interface.
+func (n *neutrinoChainBackend) WaitForBlockHeight(ctx context.Context,
+	height int32) error {
+
+	return pollForBlockHeight(ctx, n, height)
+}
+
+// pollForBlockHeight polls backend.BestBlockHeight until it reaches height,
+// ctx is done, or a poll fails.
+func pollForBlockHeight(ctx context.Context, backend chainBackend,
+	height int32) error {
+
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		current, err := backend.BestBlockHeight()
+		if err != nil {
+			return err
+		}
+
+		if current >= height {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case <-ticker.C:
+		}
+	}
+}
+
+// getBitcoinConn connects to the bitcoin backend configured in cfg, which is
+// either a direct JSON-RPC connection to a full node, or an SPV connection
+// via neutrino.
+func getBitcoinConn(t *testing.T, cfg *BitcoinConfig) chainBackend {
+	switch cfg.Backend {
+	case "neutrino":
+		return getNeutrinoConn(t, cfg)
+
+	default:
+		return &rpcChainBackend{
+			Client: getRPCConn(t, cfg),
+		}
+	}
+}
+
+// getRPCConn creates a direct JSON-RPC connection to a full node.
+func getRPCConn(t *testing.T, cfg *BitcoinConfig) *rpcclient.Client {
+	var (
+		rpcCert []byte
+		err     error
+	)
+
+	disableTLS := cfg.TLSPath == ""
+
+	// In case we use TLS and a certificate argument is provided, we need to
+	// read that file and provide it to the RPC connection as byte slice.
+	if !disableTLS {
+		rpcCert, err = os.ReadFile(cfg.TLSPath)
+		require.NoError(t, err)
+	}
+
+	// Connect to the backend with the certs we just loaded.
+	connCfg := &rpcclient.ConnConfig{
+		Host:         fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+		User:         cfg.User,
+		Pass:         cfg.Password,
+		HTTPPostMode: true,
+		DisableTLS:   disableTLS,
+		Certificates: rpcCert,
+	}
+
+	client, err := rpcclient.New(connCfg, nil)
+	require.NoError(t, err)
+
+	return client
+}
+
+// getNeutrinoConn spins up an SPV chain backend, connects it to the
+// configured peers, and pairs it with a separate full-node connection that's
+// used solely for mining blocks on demand.
+func getNeutrinoConn(t *testing.T, cfg *BitcoinConfig) *neutrinoChainBackend {
+	require.NotNil(t, cfg.Neutrino, "neutrino backend selected but no "+
+		"neutrino config was provided")
+	require.NotNil(t, cfg.Neutrino.MiningNode, "neutrino backend "+
+		"requires a dedicated mining node")
+
+	dataDir := t.TempDir()
+
+	db, err := walletdb.Create(
+		"bdb", fmt.Sprintf("%s/neutrino.db", dataDir), true,
+		time.Minute,
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+
+	chainService, err := neutrino.NewChainService(neutrino.Config{
+		DataDir:       dataDir,
+		Database:      db,
+		ChainParams:   chaincfg.RegressionNetParams,
+		AddPeers:      cfg.Neutrino.Peers,
+		PersistToDisk: true,
+	})
+	require.NoError(t, err)
+
+	// neutrino.Config.AddPeers already registers cfg.Neutrino.Peers as
+	// persistent connections, so there's no need to also dial them via
+	// ConnectNode once the service is up.
+	require.NoError(t, chainService.Start())
+	t.Cleanup(func() {
+		require.NoError(t, chainService.Stop())
+	})
+
+	miner := getRPCConn(t, cfg.Neutrino.MiningNode)
+
+	return &neutrinoChainBackend{
+		chainService: chainService,
+		miner:        miner,
+	}
+}
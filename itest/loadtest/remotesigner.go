@@ -0,0 +1,206 @@
+package loadtest
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/lightninglabs/taproot-assets/itest"
+	"github.com/lightninglabs/taproot-assets/taprpc"
+	"github.com/lightninglabs/taproot-assets/taprpc/assetwalletrpc"
+	"github.com/lightninglabs/taproot-assets/taprpc/mintrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/signrpc"
+	"github.com/lightningnetwork/lnd/macaroons"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"gopkg.in/macaroon.v2"
+)
+
+// getRemoteSignerClient dials the remote signer backing a tapd node
+// directly, so scenarios can measure its round-trip latency independently of
+// the tapd RPC it's servicing.
+func getRemoteSignerClient(t *testing.T, ctx context.Context,
+	cfg *RemoteSignerConfig) signrpc.SignerClient {
+
+	creds := credentials.NewTLS(&tls.Config{})
+	if cfg.TLSPath != "" {
+		tlsCert, err := os.ReadFile(cfg.TLSPath)
+		require.NoError(t, err)
+
+		cp := x509.NewCertPool()
+		ok := cp.AppendCertsFromPEM(tlsCert)
+		require.True(t, ok)
+
+		creds = credentials.NewClientTLSFromCert(cp, "")
+	}
+
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithDefaultCallOptions(maxMsgRecvSize),
+	}
+
+	if cfg.MacPath != "" {
+		macBytes, err := os.ReadFile(cfg.MacPath)
+		require.NoError(t, err)
+
+		mac := &macaroon.Macaroon{}
+		err = mac.UnmarshalBinary(macBytes)
+		require.NoError(t, err)
+
+		macCred, err := macaroons.NewMacaroonCredential(mac)
+		require.NoError(t, err)
+
+		opts = append(opts, grpc.WithPerRPCCredentials(macCred))
+	}
+
+	svrAddr := fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)
+	conn, err := grpc.DialContext(ctx, svrAddr, opts...)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, conn.Close())
+	})
+
+	return signrpc.NewSignerClient(conn)
+}
+
+// testRemoteSignerLatency measures the round-trip latency of a real vPSBT
+// sign call against every node in the swarm, and separately, for nodes whose
+// config points at a remote signer, the bare signrpc round trip to that
+// remote signer.
+//
+// NOTE: tapd itself has no config flag in this repo snapshot to route its
+// Taproot output signing through cfg.RemoteSigner instead of its embedded
+// wallet (see the remotesigner.Config doc), so every node's vPSBT sign call
+// below exercises the same in-process signer regardless of whether
+// RemoteSigner is set. The two measurements are therefore reported
+// independently rather than one being subtracted from the other; treat the
+// remote-signer number as a standalone benchmark of that RPC surface, not as
+// a breakdown of the vPSBT latency above it. Once tapd gains that wiring,
+// this scenario should be split into true with/without-remote-signer runs
+// of the same vPSBT flow.
+func testRemoteSignerLatency(t *testing.T, ctx context.Context, cfg *Config) {
+	clients, bitcoinClient := initClients(t, ctx, cfg)
+
+	for name, client := range clients {
+		fundedPsbt := mintAndFundSelfTransfer(
+			t, ctx, client, bitcoinClient,
+		)
+
+		start := time.Now()
+		_, err := client.AssetWalletClient.SignVirtualPsbt(
+			ctx, &assetwalletrpc.SignVirtualPsbtRequest{
+				FundedPsbt: fundedPsbt,
+			},
+		)
+		elapsed := time.Since(start)
+		require.NoError(t, err, "node %q: sign virtual psbt", name)
+
+		t.Logf("node %q: vPSBT sign round trip (embedded signer) "+
+			"took %s", name, elapsed)
+
+		if client.cfg.RemoteSigner == nil {
+			continue
+		}
+
+		rawStart := time.Now()
+		_, err = client.remoteSigner.SignMessage(
+			ctx, &signrpc.SignMessageReq{
+				Msg: []byte("loadtest-remote-signer-probe"),
+				KeyLoc: &signrpc.KeyLocator{
+					KeyFamily: 0,
+					KeyIndex:  0,
+				},
+			},
+		)
+		rawElapsed := time.Since(rawStart)
+		require.NoError(t, err, "node %q: remote signer round trip",
+			name)
+
+		t.Logf("node %q: bare remote signer round trip took %s "+
+			"(standalone measurement, not part of the vPSBT "+
+			"sign call above)", name, rawElapsed)
+	}
+
+	itest.MineBlocks(t, bitcoinClient.MiningClient(), 1, 0)
+}
+
+// mintAndFundSelfTransfer mints a fresh asset on client, finalizes and mines
+// it, then funds (but does not sign) a virtual PSBT that transfers it to a
+// freshly derived address on the same node. It returns the funded PSBT bytes
+// ready to be signed.
+func mintAndFundSelfTransfer(t *testing.T, ctx context.Context,
+	client *rpcClient, bitcoinClient chainBackend) []byte {
+
+	name := fmt.Sprintf("loadtest-latency-%d", time.Now().UnixNano())
+
+	_, err := client.MintClient.MintAsset(ctx, &mintrpc.MintAssetRequest{
+		Asset: &mintrpc.MintAsset{
+			AssetType: taprpc.AssetType_NORMAL,
+			Name:      name,
+			AssetMeta: &taprpc.AssetMeta{
+				Data: []byte(name),
+				Type: taprpc.AssetMetaType_META_TYPE_OPAQUE,
+			},
+			Amount: 1000,
+		},
+	})
+	require.NoError(t, err, "mint asset")
+
+	_, err = client.MintClient.FinalizeBatch(
+		ctx, &mintrpc.FinalizeBatchRequest{},
+	)
+	require.NoError(t, err, "finalize batch")
+
+	itest.MineBlocks(t, bitcoinClient.MiningClient(), 1, 0)
+
+	assetID := findAssetIDByName(t, ctx, client, name)
+
+	addrResp, err := client.TaprootAssetsClient.NewAddr(
+		ctx, &taprpc.NewAddrRequest{
+			AssetId: assetID,
+			Amt:     1,
+		},
+	)
+	require.NoError(t, err, "derive address")
+
+	fundResp, err := client.AssetWalletClient.FundVirtualPsbt(
+		ctx, &assetwalletrpc.FundVirtualPsbtRequest{
+			Template: &assetwalletrpc.FundVirtualPsbtRequest_Raw{
+				Raw: &assetwalletrpc.TxTemplate{
+					Recipients: map[string]uint64{
+						addrResp.Encoded: 1,
+					},
+				},
+			},
+		},
+	)
+	require.NoError(t, err, "fund virtual psbt")
+
+	return fundResp.FundedPsbt
+}
+
+// findAssetIDByName looks up the asset ID of the just-minted, just-confirmed
+// asset with the given genesis name.
+func findAssetIDByName(t *testing.T, ctx context.Context, client *rpcClient,
+	name string) []byte {
+
+	resp, err := client.TaprootAssetsClient.ListAssets(
+		ctx, &taprpc.ListAssetRequest{},
+	)
+	require.NoError(t, err, "list assets")
+
+	for _, a := range resp.Assets {
+		if a.AssetGenesis.Name == name {
+			return a.AssetGenesis.AssetId
+		}
+	}
+
+	t.Fatalf("minted asset %q not found after mining", name)
+	return nil
+}
@@ -0,0 +1,62 @@
+package loadtest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lightninglabs/taproot-assets/taprpc"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// testSwarmThroughput spins up the full swarm configured in cfg and drives
+// concurrent mint/send/universe-sync activity across all of its nodes for
+// the given duration, so we can observe how universe reconciliation and
+// transfer throughput scale as the swarm grows from two nodes to dozens.
+func testSwarmThroughput(t *testing.T, ctx context.Context, cfg *Config,
+	duration time.Duration) {
+
+	clients, bitcoinClient := initClients(t, ctx, cfg)
+
+	runCtx, cancel := context.WithTimeout(ctx, duration)
+	defer cancel()
+
+	scheduler := newSwarmScheduler(t, cfg.Workload, clients, bitcoinClient)
+	scheduler.Run(runCtx)
+}
+
+// testExpiredMacaroonRejected dials a tapd node with a macaroon that expires
+// almost immediately, then asserts that tapd rejects a request made once the
+// time-before caveat has lapsed.
+func testExpiredMacaroonRejected(t *testing.T, ctx context.Context,
+	cfg *TapConfig) {
+
+	cfgCopy := *cfg
+	cfgCopy.MacaroonTimeout = time.Second
+
+	client := getTapClient(t, ctx, &cfgCopy)
+
+	time.Sleep(2 * time.Second)
+
+	_, err := client.GetInfo(ctx, &taprpc.GetInfoRequest{})
+	require.Error(t, err)
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+// testDisallowedSourceAddrRejected dials a tapd node with a macaroon locked
+// to a source address that doesn't match where the loadtest harness is
+// actually running from, then asserts that tapd rejects the request.
+func testDisallowedSourceAddrRejected(t *testing.T, ctx context.Context,
+	cfg *TapConfig, disallowedAddr string) {
+
+	cfgCopy := *cfg
+	cfgCopy.MacaroonIPLock = disallowedAddr
+
+	client := getTapClient(t, ctx, &cfgCopy)
+
+	_, err := client.GetInfo(ctx, &taprpc.GetInfoRequest{})
+	require.Error(t, err)
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+}
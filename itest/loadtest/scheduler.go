@@ -0,0 +1,328 @@
+package loadtest
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lightninglabs/taproot-assets/itest"
+	"github.com/lightninglabs/taproot-assets/taprpc"
+	"github.com/lightninglabs/taproot-assets/taprpc/mintrpc"
+	"github.com/lightninglabs/taproot-assets/taprpc/universerpc"
+)
+
+// activityKind identifies one of the concurrent workloads the scheduler can
+// drive against the swarm.
+type activityKind string
+
+const (
+	activityMint         activityKind = "mint"
+	activitySend         activityKind = "send"
+	activityUniverseSync activityKind = "universe-sync"
+)
+
+// activityStats tracks how many attempts of an activity kind succeeded or
+// failed, so a scenario can report real transfer/mint throughput instead of
+// just silently swallowing errors.
+type activityStats struct {
+	attempts int64
+	failures int64
+}
+
+func (s *activityStats) recordAttempt() {
+	atomic.AddInt64(&s.attempts, 1)
+}
+
+func (s *activityStats) recordFailure() {
+	atomic.AddInt64(&s.failures, 1)
+}
+
+// swarmScheduler drives concurrent mint/send/universe-sync activity across
+// an arbitrary pool of tapd nodes, so we can measure how universe
+// reconciliation and asset transfer throughput scale as the swarm grows.
+type swarmScheduler struct {
+	t       *testing.T
+	cfg     *WorkloadConfig
+	clients map[string]*rpcClient
+	bitcoin chainBackend
+
+	stats map[activityKind]*activityStats
+
+	// mintedAssetsByNode tracks, per node, the IDs of the confirmed
+	// assets that node holds, minted or received. send() only ever draws from the
+	// sending node's own entry, so a transfer attempt always targets an
+	// asset the sender actually holds instead of one picked from the
+	// swarm at large.
+	mintedMu           sync.Mutex
+	mintedAssetsByNode map[string][][]byte
+
+	wg sync.WaitGroup
+}
+
+// newSwarmScheduler creates a new scheduler for the given pool of clients.
+func newSwarmScheduler(t *testing.T, cfg *WorkloadConfig,
+	clients map[string]*rpcClient, bitcoin chainBackend) *swarmScheduler {
+
+	return &swarmScheduler{
+		t:       t,
+		cfg:     cfg,
+		clients: clients,
+		bitcoin: bitcoin,
+		stats: map[activityKind]*activityStats{
+			activityMint:         {},
+			activitySend:         {},
+			activityUniverseSync: {},
+		},
+		mintedAssetsByNode: make(map[string][][]byte, len(clients)),
+	}
+}
+
+// Run starts cfg.FanOut workers per node and activity kind, each node's
+// workers for a given activity sharing a single rate limiter so the swarm
+// never drives more than cfg.QPS requests per second against any one node.
+// Run blocks until ctx is canceled, at which point all workers are stopped
+// and a throughput summary is logged.
+func (s *swarmScheduler) Run(ctx context.Context) {
+	go s.finalizeBatchesPeriodically(ctx, time.Second)
+
+	for name, node := range s.clients {
+		s.startNodeWorkers(ctx, name, node, activityMint)
+		s.startNodeWorkers(ctx, name, node, activitySend)
+		s.startNodeWorkers(ctx, name, node, activityUniverseSync)
+	}
+
+	s.wg.Wait()
+	s.logSummary()
+}
+
+// startNodeWorkers launches cfg.FanOut workers for the given node and
+// activity kind. All of them share a single ticker so the node's aggregate
+// rate for this activity is capped at cfg.QPS, regardless of FanOut.
+func (s *swarmScheduler) startNodeWorkers(ctx context.Context, name string,
+	node *rpcClient, kind activityKind) {
+
+	limiter := time.NewTicker(qpsToInterval(s.cfg.QPS))
+
+	for i := 0; i < s.cfg.FanOut; i++ {
+		s.wg.Add(1)
+
+		go func() {
+			defer s.wg.Done()
+
+			for {
+				select {
+				case <-ctx.Done():
+					limiter.Stop()
+					return
+
+				case <-limiter.C:
+					s.runActivity(ctx, kind, name, node)
+				}
+			}
+		}()
+	}
+}
+
+// runActivity performs one unit of work for the given activity kind against
+// the given node, recording whether it succeeded.
+func (s *swarmScheduler) runActivity(ctx context.Context, kind activityKind,
+	name string, node *rpcClient) {
+
+	stats := s.stats[kind]
+	stats.recordAttempt()
+
+	var err error
+	switch kind {
+	case activityMint:
+		err = s.mint(ctx, node)
+
+	case activitySend:
+		err = s.send(ctx, name, node)
+
+	case activityUniverseSync:
+		_, err = node.UniverseClient.SyncUniverse(
+			ctx, &universerpc.SyncRequest{},
+		)
+	}
+
+	if err != nil {
+		stats.recordFailure()
+		s.t.Logf("%s on node %q failed: %v", kind, name, err)
+	}
+}
+
+// mint issues a real mint request for a freshly named normal asset. The
+// batch is finalized later by finalizeBatchesPeriodically; once confirmed
+// it's picked up as a transfer candidate by refreshMintedAssets.
+func (s *swarmScheduler) mint(ctx context.Context, node *rpcClient) error {
+	name := fmt.Sprintf("loadtest-%d", rand.Int63())
+
+	_, err := node.MintClient.MintAsset(ctx, &mintrpc.MintAssetRequest{
+		Asset: &mintrpc.MintAsset{
+			AssetType: taprpc.AssetType_NORMAL,
+			Name:      name,
+			AssetMeta: &taprpc.AssetMeta{
+				Data: []byte(name),
+				Type: taprpc.AssetMetaType_META_TYPE_OPAQUE,
+			},
+			Amount: 1000,
+		},
+	})
+	return err
+}
+
+// send transfers a confirmed asset that node itself holds (minted or
+// received) to a freshly derived address on a different node in the swarm. If node doesn't
+// hold any confirmed asset yet, it's a no-op rather than a failure, since the
+// workload is still ramping up.
+func (s *swarmScheduler) send(ctx context.Context, name string,
+	node *rpcClient) error {
+
+	assetID, ok := s.randomMintedAsset(name)
+	if !ok {
+		return nil
+	}
+
+	recipient := s.randomOtherNode(name)
+	if recipient == nil {
+		return nil
+	}
+
+	addrResp, err := recipient.TaprootAssetsClient.NewAddr(
+		ctx, &taprpc.NewAddrRequest{
+			AssetId: assetID,
+			Amt:     1,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("deriving recipient address: %w", err)
+	}
+
+	_, err = node.TaprootAssetsClient.SendAsset(
+		ctx, &taprpc.SendAssetRequest{
+			TapAddrs: []string{addrResp.Encoded},
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("sending asset: %w", err)
+	}
+
+	return nil
+}
+
+// finalizeBatchesPeriodically finalizes every node's pending mint batch on
+// a timer, mines a block to confirm it, then records any newly confirmed
+// assets so send() has real transfer candidates.
+func (s *swarmScheduler) finalizeBatchesPeriodically(ctx context.Context,
+	every time.Duration) {
+
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			for name, node := range s.clients {
+				s.finalizeBatch(ctx, name, node)
+			}
+
+			itest.MineBlocks(s.t, s.bitcoin.MiningClient(), 1, 0)
+			s.refreshMintedAssets(ctx)
+		}
+	}
+}
+
+// finalizeBatch finalizes node's pending mint batch, if any.
+func (s *swarmScheduler) finalizeBatch(ctx context.Context, name string,
+	node *rpcClient) {
+
+	_, err := node.MintClient.FinalizeBatch(
+		ctx, &mintrpc.FinalizeBatchRequest{},
+	)
+	if err != nil {
+		s.t.Logf("finalizing batch on node %q failed: %v", name, err)
+	}
+}
+
+// refreshMintedAssets re-scans every node's confirmed assets and records
+// their IDs as transfer candidates for send(), keyed by the node that
+// actually holds each asset.
+func (s *swarmScheduler) refreshMintedAssets(ctx context.Context) {
+	byNode := make(map[string][][]byte, len(s.clients))
+	for name, node := range s.clients {
+		resp, err := node.TaprootAssetsClient.ListAssets(
+			ctx, &taprpc.ListAssetRequest{},
+		)
+		if err != nil {
+			continue
+		}
+
+		ids := make([][]byte, 0, len(resp.Assets))
+		for _, a := range resp.Assets {
+			ids = append(ids, a.AssetGenesis.AssetId)
+		}
+
+		byNode[name] = ids
+	}
+
+	s.mintedMu.Lock()
+	s.mintedAssetsByNode = byNode
+	s.mintedMu.Unlock()
+}
+
+// randomMintedAsset returns a random confirmed asset ID held by owner, if
+// any are known.
+func (s *swarmScheduler) randomMintedAsset(owner string) ([]byte, bool) {
+	s.mintedMu.Lock()
+	defer s.mintedMu.Unlock()
+
+	ids := s.mintedAssetsByNode[owner]
+	if len(ids) == 0 {
+		return nil, false
+	}
+
+	return ids[rand.Intn(len(ids))], true
+}
+
+// randomOtherNode picks a random node out of the swarm pool, excluding the
+// given name. It returns nil if no other node exists.
+func (s *swarmScheduler) randomOtherNode(exclude string) *rpcClient {
+	names := make([]string, 0, len(s.clients))
+	for name := range s.clients {
+		if name != exclude {
+			names = append(names, name)
+		}
+	}
+
+	if len(names) == 0 {
+		return nil
+	}
+
+	return s.clients[names[rand.Intn(len(names))]]
+}
+
+// logSummary logs the attempt/failure counts for every activity kind.
+func (s *swarmScheduler) logSummary() {
+	for kind, stats := range s.stats {
+		s.t.Logf("%s: %d attempts, %d failures", kind,
+			atomic.LoadInt64(&stats.attempts),
+			atomic.LoadInt64(&stats.failures))
+	}
+}
+
+// qpsToInterval converts a per-node QPS limit into the tick interval a
+// time.Ticker needs to enforce it.
+func qpsToInterval(qps float64) time.Duration {
+	if qps <= 0 {
+		return time.Millisecond
+	}
+
+	return time.Duration(float64(time.Second) / qps)
+}
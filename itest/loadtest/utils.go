@@ -2,19 +2,17 @@ package loadtest
 
 import (
 	"context"
-	"crypto/tls"
-	"crypto/x509"
 	"fmt"
 	"os"
 	"testing"
 
-	"github.com/btcsuite/btcd/rpcclient"
 	"github.com/lightninglabs/taproot-assets/itest"
 	"github.com/lightninglabs/taproot-assets/taprpc"
 	"github.com/lightninglabs/taproot-assets/taprpc/assetwalletrpc"
 	"github.com/lightninglabs/taproot-assets/taprpc/mintrpc"
 	"github.com/lightninglabs/taproot-assets/taprpc/universerpc"
 	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/signrpc"
 	"github.com/lightningnetwork/lnd/macaroons"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
@@ -34,53 +32,59 @@ type rpcClient struct {
 	universerpc.UniverseClient
 	mintrpc.MintClient
 	assetwalletrpc.AssetWalletClient
+
+	// remoteSigner is the client used to talk directly to this node's
+	// remote signer, if it was configured with one. It is nil for nodes
+	// using the embedded LND wallet.
+	remoteSigner signrpc.SignerClient
 }
 
+// initClients connects to every tapd node in the swarm plus the shared
+// bitcoin backend. The returned clients are indexed by NodeConfig.Name, so
+// scenarios can pick whatever pairs or groups they need out of the pool
+// instead of assuming a fixed two-node topology.
 func initClients(t *testing.T, ctx context.Context,
-	cfg *Config) (*rpcClient, *rpcClient, *rpcclient.Client) {
+	cfg *Config) (map[string]*rpcClient, chainBackend) {
 
-	// Create tapd clients.
-	alice := getTapClient(t, ctx, cfg.Alice.Tapd)
+	require.NotEmpty(t, cfg.Nodes, "no tapd nodes configured")
 
-	_, err := alice.GetInfo(ctx, &taprpc.GetInfoRequest{})
-	require.NoError(t, err)
+	clients := make(map[string]*rpcClient, len(cfg.Nodes))
+	for _, node := range cfg.Nodes {
+		client := getTapClient(t, ctx, node.Tapd)
 
-	bob := getTapClient(t, ctx, cfg.Bob.Tapd)
+		_, err := client.GetInfo(ctx, &taprpc.GetInfoRequest{})
+		require.NoError(t, err)
 
-	_, err = bob.GetInfo(ctx, &taprpc.GetInfoRequest{})
-	require.NoError(t, err)
+		clients[node.Name] = client
+	}
 
 	// Create bitcoin client.
 	bitcoinClient := getBitcoinConn(t, cfg.Bitcoin)
 
-	// Test bitcoin client connection by mining a block.
-	itest.MineBlocks(t, bitcoinClient, 1, 0)
+	// Test bitcoin client connection by mining a block, then make sure
+	// this backend actually observed it before we start using it. For
+	// the SPV backend this proves the neutrino client is really synced
+	// to the mining node's peers and not just idling.
+	itest.MineBlocks(t, bitcoinClient.MiningClient(), 1, 0)
+
+	height, err := bitcoinClient.MiningClient().GetBlockCount()
+	require.NoError(t, err)
+	require.NoError(t, bitcoinClient.WaitForBlockHeight(ctx, int32(height)))
 
 	// If we fail from this point onward, we might have created a
 	// transaction that isn't mined yet. To make sure we can run the test
 	// again, we'll make sure to clean up the mempool by mining a block.
 	t.Cleanup(func() {
-		itest.MineBlocks(t, bitcoinClient, 1, 0)
+		itest.MineBlocks(t, bitcoinClient.MiningClient(), 1, 0)
 	})
 
-	return alice, bob, bitcoinClient
+	return clients, bitcoinClient
 }
 
 func getTapClient(t *testing.T, ctx context.Context,
 	cfg *TapConfig) *rpcClient {
 
-	creds := credentials.NewTLS(&tls.Config{})
-	if cfg.TLSPath != "" {
-		// Load the certificate file now, if specified.
-		tlsCert, err := os.ReadFile(cfg.TLSPath)
-		require.NoError(t, err)
-
-		cp := x509.NewCertPool()
-		ok := cp.AppendCertsFromPEM(tlsCert)
-		require.True(t, ok)
-
-		creds = credentials.NewClientTLSFromCert(cp, "")
-	}
+	creds := credentials.NewTLS(buildTLSConfig(t, cfg))
 
 	// Create a dial options array.
 	opts := []grpc.DialOption{
@@ -97,6 +101,8 @@ func getTapClient(t *testing.T, ctx context.Context,
 		err = mac.UnmarshalBinary(macBytes)
 		require.NoError(t, err)
 
+		mac = constrainMacaroon(t, cfg, mac)
+
 		macCred, err := macaroons.NewMacaroonCredential(mac)
 		require.NoError(t, err)
 
@@ -120,6 +126,12 @@ func getTapClient(t *testing.T, ctx context.Context,
 		AssetWalletClient:   assetWalletClient,
 	}
 
+	if cfg.RemoteSigner != nil {
+		client.remoteSigner = getRemoteSignerClient(
+			t, ctx, cfg.RemoteSigner,
+		)
+	}
+
 	t.Cleanup(func() {
 		err := conn.Close()
 		require.NoError(t, err)
@@ -127,34 +139,3 @@ func getTapClient(t *testing.T, ctx context.Context,
 
 	return client
 }
-
-func getBitcoinConn(t *testing.T, cfg *BitcoinConfig) *rpcclient.Client {
-	var (
-		rpcCert []byte
-		err     error
-	)
-
-	disableTLS := cfg.TLSPath == ""
-
-	// In case we use TLS and a certificate argument is provided, we need to
-	// read that file and provide it to the RPC connection as byte slice.
-	if !disableTLS {
-		rpcCert, err = os.ReadFile(cfg.TLSPath)
-		require.NoError(t, err)
-	}
-
-	// Connect to the backend with the certs we just loaded.
-	connCfg := &rpcclient.ConnConfig{
-		Host:         fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
-		User:         cfg.User,
-		Pass:         cfg.Password,
-		HTTPPostMode: true,
-		DisableTLS:   disableTLS,
-		Certificates: rpcCert,
-	}
-
-	client, err := rpcclient.New(connCfg, nil)
-	require.NoError(t, err)
-
-	return client
-}
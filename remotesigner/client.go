@@ -0,0 +1,230 @@
+package remotesigner
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnrpc/signrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/walletrpc"
+	"github.com/lightningnetwork/lnd/macaroons"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"gopkg.in/macaroon.v2"
+)
+
+// dial opens a gRPC connection to the remote signer described by cfg.
+func dial(cfg *Config) (*grpc.ClientConn, error) {
+	creds := credentials.NewTLS(&tls.Config{})
+	if cfg.TLSPath != "" {
+		tlsCert, err := os.ReadFile(cfg.TLSPath)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read remote "+
+				"signer TLS cert: %w", err)
+		}
+
+		cp := x509.NewCertPool()
+		if !cp.AppendCertsFromPEM(tlsCert) {
+			return nil, fmt.Errorf("unable to parse remote " +
+				"signer TLS cert")
+		}
+
+		creds = credentials.NewClientTLSFromCert(cp, "")
+	}
+
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+	}
+
+	if cfg.MacPath != "" {
+		macBytes, err := os.ReadFile(cfg.MacPath)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read remote "+
+				"signer macaroon: %w", err)
+		}
+
+		mac := &macaroon.Macaroon{}
+		if err := mac.UnmarshalBinary(macBytes); err != nil {
+			return nil, fmt.Errorf("unable to decode remote "+
+				"signer macaroon: %w", err)
+		}
+
+		macCred, err := macaroons.NewMacaroonCredential(mac)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create macaroon "+
+				"credential: %w", err)
+		}
+
+		opts = append(opts, grpc.WithPerRPCCredentials(macCred))
+	}
+
+	return grpc.Dial(cfg.RPCHost, opts...)
+}
+
+// RPCSigner delegates the raw-output signing requests tapd issues (signing
+// a Taproot output via a SignDescriptor) to a remote signer process over
+// gRPC, so that no private key material ever needs to live in the calling
+// process.
+//
+// NOTE: this only covers the SignOutputRaw/ComputeInputScript surface of
+// lnd's input.Signer interface. The full interface additionally requires
+// the MuSig2 session methods (MuSig2CreateSession, MuSig2RegisterNonces,
+// MuSig2Sign, MuSig2CombineSig, MuSig2Cleanup), which aren't implemented
+// here yet; RPCSigner is not a drop-in input.Signer until those are added.
+// signrpc.SignerClient already exposes the matching MuSig2 RPCs on the
+// remote signer side, so wiring them up is a matter of proxying, not a
+// remote-signer limitation.
+type RPCSigner struct {
+	client signrpc.SignerClient
+}
+
+// Compile-time checks that RPCSigner and RPCKeyRing satisfy the subset of
+// lnd's input.Signer and keychain.KeyRing interfaces that tapd's Taproot
+// output signing path actually exercises today.
+var (
+	_ baseSigner       = (*RPCSigner)(nil)
+	_ keychain.KeyRing = (*RPCKeyRing)(nil)
+)
+
+// baseSigner is the non-MuSig2 subset of lnd's input.Signer interface.
+type baseSigner interface {
+	SignOutputRaw(tx *wire.MsgTx,
+		signDesc *input.SignDescriptor) (input.Signature, error)
+
+	ComputeInputScript(tx *wire.MsgTx,
+		signDesc *input.SignDescriptor) (*input.Script, error)
+}
+
+// NewRPCSigner dials the remote signer described by cfg and returns an
+// RPCSigner backed by it, which implements the non-MuSig2 subset of
+// input.Signer.
+func NewRPCSigner(cfg *Config) (*RPCSigner, error) {
+	conn, err := dial(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RPCSigner{
+		client: signrpc.NewSignerClient(conn),
+	}, nil
+}
+
+// SignOutputRaw requests a signature for the given output from the remote
+// signer.
+//
+// NOTE: This is part of the input.Signer interface.
+func (r *RPCSigner) SignOutputRaw(tx *wire.MsgTx,
+	signDesc *input.SignDescriptor) (input.Signature, error) {
+
+	rawTx, err := toSignReq(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.client.SignOutputRaw(
+		context.Background(), &signrpc.SignReq{
+			RawTxBytes: rawTx,
+			SignDescs: []*signrpc.SignDescriptor{
+				marshalSignDescriptor(signDesc),
+			},
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("remote signer: %w", err)
+	}
+
+	return parseSignature(resp)
+}
+
+// ComputeInputScript requests a full input script (sig + witness) for the
+// given input from the remote signer.
+//
+// NOTE: This is part of the input.Signer interface.
+func (r *RPCSigner) ComputeInputScript(tx *wire.MsgTx,
+	signDesc *input.SignDescriptor) (*input.Script, error) {
+
+	rawTx, err := toSignReq(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.client.ComputeInputScript(
+		context.Background(), &signrpc.SignReq{
+			RawTxBytes: rawTx,
+			SignDescs: []*signrpc.SignDescriptor{
+				marshalSignDescriptor(signDesc),
+			},
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("remote signer: %w", err)
+	}
+
+	return parseInputScript(resp)
+}
+
+// RPCKeyRing is a keychain.KeyRing implementation that asks a remote signer
+// process to derive keys on its behalf, so the extended master key never
+// needs to be loaded by tapd itself.
+type RPCKeyRing struct {
+	client walletrpc.WalletKitClient
+}
+
+// NewRPCKeyRing dials the remote signer described by cfg and returns a
+// keychain.KeyRing backed by it.
+func NewRPCKeyRing(cfg *Config) (*RPCKeyRing, error) {
+	conn, err := dial(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RPCKeyRing{
+		client: walletrpc.NewWalletKitClient(conn),
+	}, nil
+}
+
+// DeriveNextKey asks the remote signer to derive and return the next key in
+// the given key family.
+//
+// NOTE: This is part of the keychain.KeyRing interface.
+func (r *RPCKeyRing) DeriveNextKey(
+	keyFam keychain.KeyFamily) (keychain.KeyDescriptor, error) {
+
+	resp, err := r.client.DeriveNextKey(
+		context.Background(), &walletrpc.KeyReq{
+			KeyFamily: int32(keyFam),
+		},
+	)
+	if err != nil {
+		return keychain.KeyDescriptor{}, fmt.Errorf("remote "+
+			"signer: %w", err)
+	}
+
+	return parseKeyDescriptor(resp)
+}
+
+// DeriveKey asks the remote signer to derive and return the key at the given
+// key locator.
+//
+// NOTE: This is part of the keychain.KeyRing interface.
+func (r *RPCKeyRing) DeriveKey(
+	keyLoc keychain.KeyLocator) (keychain.KeyDescriptor, error) {
+
+	resp, err := r.client.DeriveKey(
+		context.Background(), &signrpc.KeyLocator{
+			KeyFamily: int32(keyLoc.Family),
+			KeyIndex:  int32(keyLoc.Index),
+		},
+	)
+	if err != nil {
+		return keychain.KeyDescriptor{}, fmt.Errorf("remote "+
+			"signer: %w", err)
+	}
+
+	return parseKeyDescriptor(resp)
+}
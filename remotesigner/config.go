@@ -0,0 +1,30 @@
+package remotesigner
+
+// Config holds the connection details needed to reach a remote signer
+// process. The remote signer answers signing and key-derivation requests
+// over gRPC while the private key material it guards never leaves its own
+// host, mirroring the "remote wallet" deployment model lnd supports for its
+// own wallet.
+//
+// NOTE: this package only provides the client side of that model (dialing
+// the remote signer and exposing it as a keychain.KeyRing/baseSigner).
+// Selecting this backend instead of the embedded LND wallet is a tapd
+// daemon config concern, and tapd's daemon/config code isn't part of this
+// repo snapshot, so that selection logic and an itest that forces a tapd
+// node through it genuinely can't be landed from here. Until that wiring
+// exists, no itest in this repo actually exercises RPCSigner/RPCKeyRing from
+// inside a running tapd process. itest/loadtest/remotesigner.go's bare
+// signrpc latency probe dials the remote signer with its own
+// signrpc.SignerClient and doesn't go through them either.
+type Config struct {
+	// RPCHost is the host:port the remote signer's gRPC interface is
+	// listening on.
+	RPCHost string
+
+	// TLSPath is the path to the remote signer's TLS certificate.
+	TLSPath string
+
+	// MacPath is the path to the macaroon used to authenticate against
+	// the remote signer.
+	MacPath string
+}
@@ -0,0 +1,133 @@
+package remotesigner
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnrpc/signrpc"
+)
+
+// schnorrSigLen is the fixed length of a BIP-340 Schnorr signature: a raw
+// 64-byte (R, s) pair with no DER encoding and no trailing sighash-type
+// byte. It's how parseSignature tells a Taproot key-spend signature apart
+// from the variable-length ECDSA signatures used for pre-Taproot inputs.
+const schnorrSigLen = 64
+
+// toSignReq serializes tx into the raw bytes the remote signer expects.
+func toSignReq(tx *wire.MsgTx) ([]byte, error) {
+	var txBuf bytes.Buffer
+	if err := tx.Serialize(&txBuf); err != nil {
+		return nil, fmt.Errorf("unable to serialize tx: %w", err)
+	}
+
+	return txBuf.Bytes(), nil
+}
+
+// marshalSignDescriptor converts a local input.SignDescriptor into its gRPC
+// representation.
+func marshalSignDescriptor(
+	signDesc *input.SignDescriptor) *signrpc.SignDescriptor {
+
+	return &signrpc.SignDescriptor{
+		KeyDesc: &signrpc.KeyDescriptor{
+			RawKeyBytes: signDesc.KeyDesc.PubKey.SerializeCompressed(),
+			KeyLoc: &signrpc.KeyLocator{
+				KeyFamily: int32(signDesc.KeyDesc.Family),
+				KeyIndex:  int32(signDesc.KeyDesc.Index),
+			},
+		},
+		SingleTweak:   signDesc.SingleTweak,
+		TapTweak:      signDesc.TapTweak,
+		WitnessScript: signDesc.WitnessScript,
+		Output: &signrpc.TxOut{
+			Value:    signDesc.Output.Value,
+			PkScript: signDesc.Output.PkScript,
+		},
+		Sighash:    uint32(signDesc.HashType),
+		InputIndex: int32(signDesc.InputIndex),
+		SignMethod: marshalSignMethod(signDesc.SignMethod),
+	}
+}
+
+// marshalSignMethod converts the wallet's notion of which signing method an
+// input needs into the matching signrpc enum value, so the remote signer
+// knows to produce a Taproot Schnorr signature instead of defaulting to
+// SegWit v0 ECDSA.
+func marshalSignMethod(method input.SignMethod) signrpc.SignMethod {
+	switch method {
+	case input.TaprootKeySpendBIP0086SignMethod:
+		return signrpc.SignMethod_SIGN_METHOD_TAPROOT_KEY_SPEND_BIP0086
+
+	case input.TaprootKeySpendSignMethod:
+		return signrpc.SignMethod_SIGN_METHOD_TAPROOT_KEY_SPEND
+
+	case input.TaprootScriptSpendSignMethod:
+		return signrpc.SignMethod_SIGN_METHOD_TAPROOT_SCRIPT_SPEND
+
+	default:
+		return signrpc.SignMethod_SIGN_METHOD_WITNESS_V0
+	}
+}
+
+// parseSignature extracts the raw signature returned by the remote signer,
+// distinguishing a BIP-340 Schnorr signature (used for Taproot key-spend
+// inputs) from a DER-encoded ECDSA one by its fixed length.
+func parseSignature(resp *signrpc.SignResp) (input.Signature, error) {
+	if len(resp.RawSigs) == 0 {
+		return nil, fmt.Errorf("remote signer returned no signature")
+	}
+
+	rawSig := resp.RawSigs[0]
+	if len(rawSig) == schnorrSigLen {
+		return schnorr.ParseSignature(rawSig)
+	}
+
+	return input.NewSignatureFromBytes(rawSig)
+}
+
+// parseInputScript builds an input.Script out of the remote signer's
+// response.
+func parseInputScript(
+	resp *signrpc.InputScriptResp) (*input.Script, error) {
+
+	if len(resp.InputScripts) == 0 {
+		return nil, fmt.Errorf("remote signer returned no input script")
+	}
+
+	script := resp.InputScripts[0]
+
+	return &input.Script{
+		SigScript: script.SigScript,
+		Witness:   script.Witness,
+	}, nil
+}
+
+// parseKeyDescriptor converts the remote signer's key descriptor response
+// back into a local keychain.KeyDescriptor.
+func parseKeyDescriptor(
+	resp *signrpc.KeyDescriptor) (keychain.KeyDescriptor, error) {
+
+	pubKey, err := parsePubKey(resp.RawKeyBytes)
+	if err != nil {
+		return keychain.KeyDescriptor{}, err
+	}
+
+	return keychain.KeyDescriptor{
+		PubKey: pubKey,
+		KeyLocator: keychain.KeyLocator{
+			Family: keychain.KeyFamily(resp.KeyLoc.KeyFamily),
+			Index:  uint32(resp.KeyLoc.KeyIndex),
+		},
+	}, nil
+}
+
+// parsePubKey decodes a compressed public key as returned by the remote
+// signer.
+func parsePubKey(rawKeyBytes []byte) (*btcec.PublicKey, error) {
+	return btcec.ParsePubKey(rawKeyBytes)
+}